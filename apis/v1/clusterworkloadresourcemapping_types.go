@@ -0,0 +1,89 @@
+/*
+Copyright 2021 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterWorkloadResourceMapping is the Schema for the clusterworkloadresourcemappings API, describing
+// how to find PodSpec-like fields for workload resources that do not use the PodSpec convention.
+type ClusterWorkloadResourceMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterWorkloadResourceMappingSpec `json:"spec,omitempty"`
+}
+
+// ClusterWorkloadResourceMappingSpec defines the desired state of ClusterWorkloadResourceMapping
+type ClusterWorkloadResourceMappingSpec struct {
+	// Versions is the collection of versions for a given resource, with mappings for that specific version
+	// of the resource. The version value is matched on a best effort basis.
+	Versions []ClusterWorkloadResourceMappingTemplate `json:"versions"`
+}
+
+// ClusterWorkloadResourceMappingTemplate defines the mapping for a specific version of a resource
+type ClusterWorkloadResourceMappingTemplate struct {
+	// Version is the version of the workload resource that this mapping is for
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Annotations is a JSONPath to the annotations field on the workload resource. Defaults to
+	// `.metadata.annotations`
+	// +optional
+	Annotations *string `json:"annotations,omitempty"`
+
+	// Containers is the collection of JSONPaths to Container-like fields on the workload resource,
+	// corresponding to `.spec.template.spec.containers`.
+	// +optional
+	Containers []ClusterWorkloadResourceMappingContainer `json:"containers,omitempty"`
+
+	// InitContainers is the collection of JSONPaths to Container-like fields on the workload resource
+	// that run to completion before the main Containers start, corresponding to
+	// `.spec.template.spec.initContainers`.
+	// +optional
+	InitContainers []ClusterWorkloadResourceMappingContainer `json:"initContainers,omitempty"`
+
+	// EphemeralContainers is the collection of JSONPaths to Container-like fields on the workload
+	// resource used for troubleshooting a running Pod, corresponding to
+	// `.spec.template.spec.ephemeralContainers`.
+	// +optional
+	EphemeralContainers []ClusterWorkloadResourceMappingContainer `json:"ephemeralContainers,omitempty"`
+
+	// Volumes is a JSONPath to the Volumes field on the workload resource. Defaults to
+	// `.spec.template.spec.volumes`
+	// +optional
+	Volumes *string `json:"volumes,omitempty"`
+}
+
+// ClusterWorkloadResourceMappingContainer defines the JSONPaths for a Container-like entry
+type ClusterWorkloadResourceMappingContainer struct {
+	// Path is the JSONPath within the workload resource that matches a Container-like entry
+	Path string `json:"path"`
+
+	// Name is the JSONPath within the Container-like entry to the name field
+	// +optional
+	Name *string `json:"name,omitempty"`
+
+	// Env is the JSONPath within the Container-like entry to the env field
+	// +optional
+	Env *string `json:"env,omitempty"`
+
+	// VolumeMounts is the JSONPath within the Container-like entry to the volumeMounts field
+	// +optional
+	VolumeMounts *string `json:"volumeMounts,omitempty"`
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceBinding is the Schema for the servicebindings API
+type ServiceBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServiceBindingSpec   `json:"spec,omitempty"`
+	Status ServiceBindingStatus `json:"status,omitempty"`
+}
+
+// ServiceBindingSpec defines the desired state of ServiceBinding
+type ServiceBindingSpec struct {
+	// Name is the name of the service as projected into the workload container. Defaults to .metadata.name.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Type is the type of the service as projected into the workload container
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Provider is the provider of the service as projected into the workload container
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// Workload is a reference to an object
+	Workload ServiceBindingWorkloadReference `json:"workload"`
+
+	// Service is a reference to an object that fulfills the ProvisionedService duck type
+	Service corev1.ObjectReference `json:"service"`
+
+	// Env is the collection of mappings from Secret entries to environment variables
+	// +optional
+	Env []EnvMapping `json:"env,omitempty"`
+
+	// Mappings are additional bindings to include in the projected binding secret, resolved via a Go
+	// text/template referencing the other keys already present in the source Secret
+	// +optional
+	Mappings []ServiceBindingMapping `json:"mappings,omitempty"`
+
+	// Mounts project individual Secret keys to additional, well-known VolumeMount paths, for
+	// legacy applications that can't be pointed at ${SERVICE_BINDING_ROOT}
+	// +optional
+	Mounts []ServiceBindingMount `json:"mounts,omitempty"`
+}
+
+// ServiceBindingMount projects a single Secret key to an additional VolumeMount path
+type ServiceBindingMount struct {
+	// Key is the name of the Secret entry to project
+	Key string `json:"key"`
+
+	// Path is the well-known, absolute MountPath the key is projected to
+	Path string `json:"path"`
+
+	// Container restricts this mount to the named container. When empty, the mount is added to
+	// every bindable container, at the same Path.
+	// +optional
+	Container string `json:"container,omitempty"`
+}
+
+// EnvMapping defines a mapping from the value of a Secret entry to an environment variable
+type EnvMapping struct {
+	// Name is the name of the environment variable
+	Name string `json:"name"`
+
+	// Key is the name of the Secret entry to project, or a reserved pod/downward-api key
+	Key string `json:"key"`
+}
+
+// ServiceBindingMapping defines a derived, templated entry to add to the projected binding
+type ServiceBindingMapping struct {
+	// Name is the key under which the derived value is projected
+	Name string `json:"name"`
+
+	// Value is a Go text/template referencing other Secret keys by name, for example:
+	// "jdbc:postgresql://{{ .host }}:{{ .port }}/{{ .database }}"
+	Value string `json:"value"`
+}
+
+// ServiceBindingWorkloadReference defines a subset of corev1.ObjectReference with extensions
+type ServiceBindingWorkloadReference struct {
+	// API version of the referent
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Kind of the referent
+	Kind string `json:"kind"`
+
+	// Name of the referent
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Selector is a query over a set of objects to resolve to a workload
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Containers limits the scope of the binding to the named Containers. All
+	// other container kinds are left untouched. Mutually exclusive with ContainerKind.
+	// +optional
+	Containers []string `json:"containers,omitempty"`
+
+	// InitContainers limits the scope of the binding to the named InitContainers.
+	// +optional
+	InitContainers []string `json:"initContainers,omitempty"`
+
+	// EphemeralContainers limits the scope of the binding to the named EphemeralContainers.
+	// +optional
+	EphemeralContainers []string `json:"ephemeralContainers,omitempty"`
+
+	// ContainerKind restricts the kinds of containers the binding is projected into. It is
+	// consulted separately for each container kind whose own name list
+	// (Containers/InitContainers/EphemeralContainers) is empty. One of Container,
+	// InitContainer, EphemeralContainer. Defaults to Container for backwards compatibility.
+	// +optional
+	ContainerKind ContainerKind `json:"containerKind,omitempty"`
+}
+
+// ContainerKind names the kind of container within a PodSpec a binding may target
+type ContainerKind string
+
+const (
+	ContainerKindContainer          ContainerKind = "Container"
+	ContainerKindInitContainer      ContainerKind = "InitContainer"
+	ContainerKindEphemeralContainer ContainerKind = "EphemeralContainer"
+)
+
+// ServiceBindingStatus defines the observed state of ServiceBinding
+type ServiceBindingStatus struct {
+	Binding *ServiceBindingSecretReference `json:"binding,omitempty"`
+}
+
+// ServiceBindingSecretReference is the Secret resolved for a ServiceBinding by a
+// resolver.Resolver, along with a content hash used to detect rotation.
+type ServiceBindingSecretReference struct {
+	// Name is the name of the resolved Secret, in the ServiceBinding's namespace
+	Name string `json:"name"`
+
+	// Hash is a content hash of the resolved Secret's data, so a reconciler watching the Secret
+	// can tell when it's rotated without diffing the full Secret every time
+	// +optional
+	Hash string `json:"hash,omitempty"`
+}
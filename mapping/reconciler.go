@@ -0,0 +1,89 @@
+/*
+Copyright 2021 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mapping
+
+import (
+	"context"
+	"fmt"
+
+	servicebindingv1 "github.com/servicebinding/runtime/apis/v1"
+)
+
+// HashAnnotation is stashed on the derived Secret so Reconcile can tell its rendered content is
+// unchanged and skip rewriting it. The templates are still rendered on every call.
+const HashAnnotation = "projector.servicebinding.io/mapping-hash"
+
+// SecretStore is the minimal Secret I/O a Reconciler needs; callers back it with a real client
+// (e.g. a controller-runtime client.Client or corev1 clientset).
+type SecretStore interface {
+	// GetData returns the data of the named Secret in namespace.
+	GetData(ctx context.Context, namespace, name string) (map[string][]byte, error)
+
+	// GetAnnotation returns the requested annotation of the named Secret, or ("", false) if the
+	// Secret or the annotation doesn't exist.
+	GetAnnotation(ctx context.Context, namespace, name, key string) (string, bool, error)
+
+	// CreateOrUpdate upserts the named Secret in namespace with data and annotations merged in.
+	CreateOrUpdate(ctx context.Context, namespace, name string, data map[string][]byte, annotations map[string]string) error
+}
+
+// Reconciler renders a ServiceBinding's Spec.Mappings against its source Secret and materializes
+// the result into a derived Secret, so the projector can reference it in the projected volume.
+type Reconciler struct {
+	Secrets SecretStore
+}
+
+// NewReconciler creates a Reconciler backed by secrets.
+func NewReconciler(secrets SecretStore) *Reconciler {
+	return &Reconciler{Secrets: secrets}
+}
+
+// Reconcile renders binding's Spec.Mappings against the data of its source Secret and
+// creates/updates the derived Secret to match, skipping the write if the rendered content hash is
+// unchanged. It returns the derived Secret's name, or an error if rendering failed -- the caller
+// is expected to surface that as a condition on the binding rather than projecting a reference to
+// a Secret that doesn't reflect the requested mappings.
+func (r *Reconciler) Reconcile(ctx context.Context, binding *servicebindingv1.ServiceBinding, namespace, sourceSecretName string) (string, error) {
+	if len(binding.Spec.Mappings) == 0 {
+		return "", nil
+	}
+
+	sourceData, err := r.Secrets.GetData(ctx, namespace, sourceSecretName)
+	if err != nil {
+		return "", fmt.Errorf("unable to read source secret %s/%s: %w", namespace, sourceSecretName, err)
+	}
+
+	derived, err := Render(binding.Spec.Mappings, sourceData)
+	if err != nil {
+		return "", fmt.Errorf("unable to render mappings for service binding %s/%s: %w", binding.Namespace, binding.Name, err)
+	}
+	hash := Hash(derived)
+
+	derivedSecretName := DerivedSecretName(sourceSecretName)
+	if existingHash, found, err := r.Secrets.GetAnnotation(ctx, namespace, derivedSecretName, HashAnnotation); err != nil {
+		return "", fmt.Errorf("unable to read derived secret %s/%s: %w", namespace, derivedSecretName, err)
+	} else if found && existingHash == hash {
+		// already up to date
+		return derivedSecretName, nil
+	}
+
+	if err := r.Secrets.CreateOrUpdate(ctx, namespace, derivedSecretName, derived, map[string]string{HashAnnotation: hash}); err != nil {
+		return "", fmt.Errorf("unable to write derived secret %s/%s: %w", namespace, derivedSecretName, err)
+	}
+
+	return derivedSecretName, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mapping evaluates ServiceBindingMapping templates against a source Secret's data to
+// produce the derived entries a reconciler stashes into a binding's derived Secret.
+package mapping
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"text/template"
+
+	servicebindingv1 "github.com/servicebinding/runtime/apis/v1"
+)
+
+// Render evaluates each mapping's template against data, the source Secret's entries keyed by
+// name, and returns the resulting derived entries. A template may reference any key in data
+// (e.g. `{{ .host }}`); referencing an undefined key is an error rather than rendering empty, so
+// a typo surfaces immediately instead of producing a silently broken binding.
+func Render(mappings []servicebindingv1.ServiceBindingMapping, data map[string][]byte) (map[string][]byte, error) {
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]string, len(data))
+	for k, v := range data {
+		values[k] = string(v)
+	}
+
+	derived := make(map[string][]byte, len(mappings))
+	for _, m := range mappings {
+		tmpl, err := template.New(m.Name).Option("missingkey=error").Parse(m.Value)
+		if err != nil {
+			return nil, fmt.Errorf("mapping %q: invalid template: %w", m.Name, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, values); err != nil {
+			return nil, fmt.Errorf("mapping %q: %w", m.Name, err)
+		}
+		derived[m.Name] = buf.Bytes()
+	}
+	return derived, nil
+}
+
+// DerivedSecretName returns the name of the derived Secret holding a binding's rendered
+// Spec.Mappings entries, given the name of its source Secret. It's deterministic so the projector
+// can reference it without round-tripping through binding status.
+func DerivedSecretName(secretName string) string {
+	return fmt.Sprintf("%s-mappings", secretName)
+}
+
+// Hash returns a stable content hash of derived Secret data, suitable for stashing as an
+// annotation on the derived Secret so a reconciler can tell whether freshly rendered data differs
+// from what the derived Secret already holds, and skip the write when it doesn't.
+func Hash(data map[string][]byte) string {
+	h := sha256.New()
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write(data[k])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mapping
+
+import (
+	"testing"
+
+	servicebindingv1 "github.com/servicebinding/runtime/apis/v1"
+)
+
+func TestRender(t *testing.T) {
+	data := map[string][]byte{
+		"host":     []byte("db.example.com"),
+		"port":     []byte("5432"),
+		"database": []byte("mydb"),
+	}
+
+	t.Run("renders templates against the source data", func(t *testing.T) {
+		mappings := []servicebindingv1.ServiceBindingMapping{
+			{Name: "jdbcUrl", Value: "jdbc:postgresql://{{ .host }}:{{ .port }}/{{ .database }}"},
+		}
+		derived, err := Render(mappings, data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "jdbc:postgresql://db.example.com:5432/mydb"
+		if got := string(derived["jdbcUrl"]); got != want {
+			t.Errorf("derived[jdbcUrl] = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("no mappings returns nil", func(t *testing.T) {
+		derived, err := Render(nil, data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if derived != nil {
+			t.Errorf("derived = %v, want nil", derived)
+		}
+	})
+
+	t.Run("invalid template syntax is an error", func(t *testing.T) {
+		mappings := []servicebindingv1.ServiceBindingMapping{
+			{Name: "broken", Value: "{{ .host "},
+		}
+		if _, err := Render(mappings, data); err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	t.Run("referencing an undefined key is an error, not an empty render", func(t *testing.T) {
+		mappings := []servicebindingv1.ServiceBindingMapping{
+			{Name: "broken", Value: "{{ .typo }}"},
+		}
+		if _, err := Render(mappings, data); err == nil {
+			t.Fatal("expected an error for an undefined key, got nil")
+		}
+	})
+}
+
+func TestHash(t *testing.T) {
+	a := map[string][]byte{"a": []byte("1"), "b": []byte("2")}
+	b := map[string][]byte{"b": []byte("2"), "a": []byte("1")}
+	c := map[string][]byte{"a": []byte("1"), "b": []byte("3")}
+
+	if Hash(a) != Hash(b) {
+		t.Error("Hash should be independent of map iteration order")
+	}
+	if Hash(a) == Hash(c) {
+		t.Error("Hash should differ when content differs")
+	}
+}
+
+func TestDerivedSecretName(t *testing.T) {
+	if got, want := DerivedSecretName("my-secret"), "my-secret-mappings"; got != want {
+		t.Errorf("DerivedSecretName() = %q, want %q", got, want)
+	}
+}
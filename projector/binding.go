@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -32,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	servicebindingv1 "github.com/servicebinding/runtime/apis/v1"
+	"github.com/servicebinding/runtime/mapping"
 )
 
 const (
@@ -48,13 +50,16 @@ var _ ServiceBindingProjector = (*serviceBindingProjector)(nil)
 
 type serviceBindingProjector struct {
 	mappingSource MappingSource
+	mappings      *mapping.Reconciler
 }
 
 // New creates a service binding projector configured for the mapping source. The binding projector is typically created
-// once and applied to multiple workloads.
-func New(mappingSource MappingSource) ServiceBindingProjector {
+// once and applied to multiple workloads. mappings may be nil if no ServiceBinding projected by this projector sets
+// Spec.Mappings; if it's unset, Project returns an error for any resolved binding that does and targets the workload.
+func New(mappingSource MappingSource, mappings *mapping.Reconciler) ServiceBindingProjector {
 	return &serviceBindingProjector{
 		mappingSource: mappingSource,
+		mappings:      mappings,
 	}
 }
 
@@ -73,6 +78,19 @@ func (p *serviceBindingProjector) Project(ctx context.Context, binding *serviceb
 		return nil
 	}
 
+	// render and materialize Spec.Mappings before touching the workload further, so a mapping
+	// error fails projection cleanly rather than leaving behind a partially unprojected workload.
+	// shouldProject already guarantees secretName(binding) is non-empty at this point, so a
+	// binding that hasn't been resolved yet still no-ops above instead of erroring here.
+	if len(binding.Spec.Mappings) != 0 {
+		if p.mappings == nil {
+			return fmt.Errorf("service binding %s/%s specifies mappings but no mapping.Reconciler is configured", binding.Namespace, binding.Name)
+		}
+		if _, err := p.mappings.Reconcile(ctx, binding, binding.Namespace, p.secretName(binding)); err != nil {
+			return err
+		}
+	}
+
 	versionMapping := MappingVersion(version, resourceMapping)
 	mpt, err := NewMetaPodTemplate(ctx, workload, versionMapping)
 	if err != nil {
@@ -184,7 +202,13 @@ func (p *serviceBindingProjector) shouldProject(binding *servicebindingv1.Servic
 func (p *serviceBindingProjector) project(binding *servicebindingv1.ServiceBinding, mpt *metaPodTemplate) {
 	p.projectVolume(binding, mpt)
 	for i := range mpt.Containers {
-		p.projectContainer(binding, mpt, &mpt.Containers[i])
+		p.projectContainer(binding, mpt, &mpt.Containers[i], servicebindingv1.ContainerKindContainer)
+	}
+	for i := range mpt.InitContainers {
+		p.projectContainer(binding, mpt, &mpt.InitContainers[i], servicebindingv1.ContainerKindInitContainer)
+	}
+	for i := range mpt.EphemeralContainers {
+		p.projectContainer(binding, mpt, &mpt.EphemeralContainers[i], servicebindingv1.ContainerKindEphemeralContainer)
 	}
 }
 
@@ -193,9 +217,16 @@ func (p *serviceBindingProjector) unproject(binding *servicebindingv1.ServiceBin
 	for i := range mpt.Containers {
 		p.unprojectContainer(binding, mpt, &mpt.Containers[i])
 	}
+	for i := range mpt.InitContainers {
+		p.unprojectContainer(binding, mpt, &mpt.InitContainers[i])
+	}
+	for i := range mpt.EphemeralContainers {
+		p.unprojectContainer(binding, mpt, &mpt.EphemeralContainers[i])
+	}
 
 	// cleanup annotations
 	delete(mpt.PodTemplateAnnotations, p.secretAnnotationName(binding))
+	delete(mpt.PodTemplateAnnotations, p.derivedSecretAnnotationName(binding))
 	delete(mpt.PodTemplateAnnotations, p.typeAnnotationName(binding))
 	delete(mpt.PodTemplateAnnotations, p.providerAnnotationName(binding))
 }
@@ -249,6 +280,40 @@ func (p *serviceBindingProjector) projectVolume(binding *servicebindingv1.Servic
 			},
 		)
 	}
+	for _, e := range binding.Spec.Env {
+		fieldPath, volumeOK := downwardAPIFieldPath(e.Key)
+		if fieldPath == "" || !volumeOK {
+			continue
+		}
+		volume.VolumeSource.Projected.Sources = append(volume.VolumeSource.Projected.Sources,
+			corev1.VolumeProjection{
+				DownwardAPI: &corev1.DownwardAPIProjection{
+					Items: []corev1.DownwardAPIVolumeFile{
+						{
+							Path: downwardAPIVolumePath(e.Key),
+							FieldRef: &corev1.ObjectFieldSelector{
+								FieldPath: fieldPath,
+							},
+						},
+					},
+				},
+			},
+		)
+	}
+	if len(binding.Spec.Mappings) != 0 {
+		// the derived Secret is materialized by Project, via mapping.Reconciler, before project
+		// runs (see package mapping); projecting it here is just a second entry in the same
+		// projected volume, alongside the raw keys
+		volume.VolumeSource.Projected.Sources = append(volume.VolumeSource.Projected.Sources,
+			corev1.VolumeProjection{
+				Secret: &corev1.SecretProjection{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: p.derivedSecretAnnotation(binding, mpt),
+					},
+				},
+			},
+		)
+	}
 
 	mpt.Volumes = append(mpt.Volumes, volume)
 
@@ -282,8 +347,8 @@ func (p *serviceBindingProjector) unprojectVolume(binding *servicebindingv1.Serv
 	mpt.Volumes = volumes
 }
 
-func (p *serviceBindingProjector) projectContainer(binding *servicebindingv1.ServiceBinding, mpt *metaPodTemplate, mc *metaContainer) {
-	if !p.isContainerBindable(binding, mc) {
+func (p *serviceBindingProjector) projectContainer(binding *servicebindingv1.ServiceBinding, mpt *metaPodTemplate, mc *metaContainer, kind servicebindingv1.ContainerKind) {
+	if !p.isContainerBindable(binding, mc, kind) {
 		return
 	}
 	p.projectVolumeMount(binding, mc)
@@ -302,6 +367,18 @@ func (p *serviceBindingProjector) projectVolumeMount(binding *servicebindingv1.S
 		MountPath: path.Join(p.serviceBindingRoot(mc), binding.Spec.Name),
 	})
 
+	for _, m := range binding.Spec.Mounts {
+		if m.Container != "" && (mc.Name == nil || *mc.Name != m.Container) {
+			continue
+		}
+		mc.VolumeMounts = append(mc.VolumeMounts, corev1.VolumeMount{
+			Name:      p.volumeName(binding),
+			ReadOnly:  true,
+			MountPath: m.Path,
+			SubPath:   m.Key,
+		})
+	}
+
 	// sort projected volume mounts
 	sort.SliceStable(mc.VolumeMounts, func(i, j int) bool {
 		ii := mc.VolumeMounts[i]
@@ -356,6 +433,17 @@ func (p *serviceBindingProjector) projectEnv(binding *servicebindingv1.ServiceBi
 			})
 			continue
 		}
+		if fieldPath, _ := downwardAPIFieldPath(e.Key); fieldPath != "" {
+			mc.Env = append(mc.Env, corev1.EnvVar{
+				Name: e.Name,
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: fieldPath,
+					},
+				},
+			})
+			continue
+		}
 		mc.Env = append(mc.Env, corev1.EnvVar{
 			Name: e.Name,
 			ValueFrom: &corev1.EnvVarSource{
@@ -394,6 +482,15 @@ func (p *serviceBindingProjector) unprojectEnv(binding *servicebindingv1.Service
 	secret := mpt.PodTemplateAnnotations[p.secretAnnotationName(binding)]
 	typeFieldPath := fmt.Sprintf("metadata.annotations['%s']", p.typeAnnotationName(binding))
 	providerFieldPath := fmt.Sprintf("metadata.annotations['%s']", p.providerAnnotationName(binding))
+	// whitelist of downward-API field paths this binding's Env entries would have produced, so env
+	// vars using reserved paths this binding doesn't use are kept; any env var using one of the
+	// paths in this set is removed, whoever added it
+	downwardFieldPaths := sets.NewString()
+	for _, e := range binding.Spec.Env {
+		if fieldPath, _ := downwardAPIFieldPath(e.Key); fieldPath != "" {
+			downwardFieldPaths.Insert(fieldPath)
+		}
+	}
 	for _, e := range mc.Env {
 		// NB we do not remove the SERVICE_BINDING_ROOT env var since we don't know if someone else is depending on it
 		remove := false
@@ -410,6 +507,10 @@ func (p *serviceBindingProjector) unprojectEnv(binding *servicebindingv1.Service
 				// custom provider env var
 				remove = true
 			}
+			if downwardFieldPaths.Has(e.ValueFrom.FieldRef.FieldPath) {
+				// projected pod/downward-api env var
+				remove = true
+			}
 		}
 		if !remove {
 			env = append(env, e)
@@ -418,11 +519,21 @@ func (p *serviceBindingProjector) unprojectEnv(binding *servicebindingv1.Service
 	mc.Env = env
 }
 
-func (p *serviceBindingProjector) isContainerBindable(binding *servicebindingv1.ServiceBinding, mc *metaContainer) bool {
-	if len(binding.Spec.Workload.Containers) == 0 || mc.Name == nil {
+func (p *serviceBindingProjector) isContainerBindable(binding *servicebindingv1.ServiceBinding, mc *metaContainer, kind servicebindingv1.ContainerKind) bool {
+	names := p.containerNamesForKind(binding, kind)
+	if names == nil {
+		// no selector configured for this container kind; fall back to the effective
+		// ContainerKind filter, defaulting to Container for backwards compatibility
+		wantKind := binding.Spec.Workload.ContainerKind
+		if wantKind == "" {
+			wantKind = servicebindingv1.ContainerKindContainer
+		}
+		return wantKind == kind
+	}
+	if len(names) == 0 || mc.Name == nil {
 		return true
 	}
-	for _, name := range binding.Spec.Workload.Containers {
+	for _, name := range names {
 		if name == *mc.Name {
 			return true
 		}
@@ -430,6 +541,73 @@ func (p *serviceBindingProjector) isContainerBindable(binding *servicebindingv1.
 	return false
 }
 
+// containerNamesForKind returns the configured container name selector for the given container
+// kind, or nil if the binding's workload reference doesn't configure a selector for that kind.
+// Only Containers can be an empty, match-everything selector; an empty InitContainers or
+// EphemeralContainers list counts as unconfigured and yields nil.
+func (p *serviceBindingProjector) containerNamesForKind(binding *servicebindingv1.ServiceBinding, kind servicebindingv1.ContainerKind) []string {
+	switch kind {
+	case servicebindingv1.ContainerKindInitContainer:
+		if len(binding.Spec.Workload.InitContainers) == 0 {
+			return nil
+		}
+		return binding.Spec.Workload.InitContainers
+	case servicebindingv1.ContainerKindEphemeralContainer:
+		if len(binding.Spec.Workload.EphemeralContainers) == 0 {
+			return nil
+		}
+		return binding.Spec.Workload.EphemeralContainers
+	default:
+		return binding.Spec.Workload.Containers
+	}
+}
+
+// downwardAPIReservedFieldPaths maps reserved Spec.Env[].Key values to the downward API field
+// path that should back them, mirroring the existing "type"/"provider" special-casing above.
+// volumeSupported reflects the real constraint that DownwardAPIVolumeFile only accepts a subset
+// of field paths (metadata.{name,namespace,uid,labels,annotations}); the rest are env-only.
+var downwardAPIReservedFieldPaths = map[string]struct {
+	fieldPath       string
+	volumeSupported bool
+}{
+	"pod.name":               {"metadata.name", true},
+	"pod.namespace":          {"metadata.namespace", true},
+	"pod.uid":                {"metadata.uid", true},
+	"pod.ip":                 {"status.podIP", false},
+	"pod.ips":                {"status.podIPs", false},
+	"pod.serviceAccountName": {"spec.serviceAccountName", false},
+	"node.name":              {"spec.nodeName", false},
+}
+
+var downwardAPILabelPattern = regexp.MustCompile(`^pod\.labels\['(.+)'\]$`)
+var downwardAPIAnnotationPattern = regexp.MustCompile(`^pod\.annotations\['(.+)'\]$`)
+
+// downwardAPIFieldPath returns the downward API field path for a reserved Spec.Env[].Key value
+// (e.g. "pod.name", "pod.labels['app']"), and whether that field path may be projected into a
+// DownwardAPIVolumeFile in addition to an env var. An empty fieldPath means key isn't reserved.
+func downwardAPIFieldPath(key string) (fieldPath string, volumeSupported bool) {
+	if reserved, ok := downwardAPIReservedFieldPaths[key]; ok {
+		return reserved.fieldPath, reserved.volumeSupported
+	}
+	if m := downwardAPILabelPattern.FindStringSubmatch(key); m != nil {
+		return fmt.Sprintf("metadata.labels['%s']", m[1]), true
+	}
+	if m := downwardAPIAnnotationPattern.FindStringSubmatch(key); m != nil {
+		return fmt.Sprintf("metadata.annotations['%s']", m[1]), true
+	}
+	return "", false
+}
+
+// downwardAPIPathReplacer flattens a reserved Spec.Env[].Key into a safe DownwardAPIVolumeFile
+// Path. Label/annotation keys are conventionally prefixed ("app.kubernetes.io/name"), and a "/"
+// in Path makes kubelet create a nested directory instead of the single flat file the rest of
+// this feature (and the sibling env var) assumes, so slashes are flattened to underscores.
+var downwardAPIPathReplacer = strings.NewReplacer("/", "_")
+
+func downwardAPIVolumePath(key string) string {
+	return downwardAPIPathReplacer.Replace(key)
+}
+
 func (p *serviceBindingProjector) serviceBindingRoot(mc *metaContainer) string {
 	for _, e := range mc.Env {
 		if e.Name == ServiceBindingRootEnv {
@@ -488,6 +666,31 @@ func (p *serviceBindingProjector) secretAnnotationName(binding *servicebindingv1
 	return fmt.Sprintf("%s%s", SecretAnnotationPrefix, binding.UID)
 }
 
+// derivedSecretAnnotation stashes and returns the name of the Secret holding the rendered
+// binding.Spec.Mappings entries. Its name is derived deterministically from the source Secret's
+// name so the projector never needs to round-trip through binding status to find it.
+func (p *serviceBindingProjector) derivedSecretAnnotation(binding *servicebindingv1.ServiceBinding, mpt *metaPodTemplate) string {
+	key := p.derivedSecretAnnotationName(binding)
+	secret := p.derivedSecretName(binding)
+	if secret == "" {
+		return ""
+	}
+	mpt.PodTemplateAnnotations[key] = secret
+	return secret
+}
+
+func (p *serviceBindingProjector) derivedSecretAnnotationName(binding *servicebindingv1.ServiceBinding) string {
+	return fmt.Sprintf("%s%s-derived", SecretAnnotationPrefix, binding.UID)
+}
+
+func (p *serviceBindingProjector) derivedSecretName(binding *servicebindingv1.ServiceBinding) string {
+	secret := p.secretName(binding)
+	if secret == "" {
+		return ""
+	}
+	return mapping.DerivedSecretName(secret)
+}
+
 func (p *serviceBindingProjector) volumeName(binding *servicebindingv1.ServiceBinding) string {
 	return fmt.Sprintf("%s%s", VolumePrefix, binding.UID)
 }
@@ -0,0 +1,267 @@
+/*
+Copyright 2021 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projector
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	servicebindingv1 "github.com/servicebinding/runtime/apis/v1"
+	"github.com/servicebinding/runtime/mapping"
+)
+
+// fakeMappingSource resolves every workload to the conventional PodTemplateSpec shape, as if no
+// ClusterWorkloadResourceMapping were registered for its GroupVersionResource.
+type fakeMappingSource struct{}
+
+func (fakeMappingSource) LookupRESTMapping(ctx context.Context, workload runtime.Object) (*meta.RESTMapping, error) {
+	return &meta.RESTMapping{
+		Resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+	}, nil
+}
+
+func (fakeMappingSource) LookupWorkloadMapping(ctx context.Context, resource schema.GroupVersionResource) (*servicebindingv1.ClusterWorkloadResourceMappingSpec, error) {
+	return nil, nil
+}
+
+func newTestDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app"}},
+				},
+			},
+		},
+	}
+}
+
+func TestProject_NoopsWhenBindingIsUnresolved(t *testing.T) {
+	// regression test: a binding with Spec.Mappings set but no resolved Status.Binding must no-op
+	// like every other unresolved binding, not hard-error out of the mapping-reconcile block.
+	p := New(fakeMappingSource{}, nil)
+	binding := &servicebindingv1.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-binding", Namespace: "default"},
+		Spec: servicebindingv1.ServiceBindingSpec{
+			Workload: servicebindingv1.ServiceBindingWorkloadReference{Kind: "Deployment", Name: "my-app"},
+			Mappings: []servicebindingv1.ServiceBindingMapping{
+				{Name: "uri", Value: "{{ .host }}"},
+			},
+		},
+	}
+	workload := newTestDeployment()
+
+	if err := p.Project(context.Background(), binding, workload); err != nil {
+		t.Fatalf("Project() returned an error for an unresolved binding: %v", err)
+	}
+	if len(workload.Spec.Template.Spec.Volumes) != 0 {
+		t.Errorf("workload was projected into despite the binding being unresolved: %+v", workload.Spec.Template.Spec.Volumes)
+	}
+}
+
+func TestProject_MissingReconcilerErrorsWhenMappingsConfigured(t *testing.T) {
+	p := New(fakeMappingSource{}, nil)
+	binding := &servicebindingv1.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-binding", Namespace: "default"},
+		Spec: servicebindingv1.ServiceBindingSpec{
+			Workload: servicebindingv1.ServiceBindingWorkloadReference{Kind: "Deployment", Name: "my-app"},
+			Mappings: []servicebindingv1.ServiceBindingMapping{
+				{Name: "uri", Value: "{{ .host }}"},
+			},
+		},
+		Status: servicebindingv1.ServiceBindingStatus{
+			Binding: &servicebindingv1.ServiceBindingSecretReference{Name: "my-secret"},
+		},
+	}
+	workload := newTestDeployment()
+
+	if err := p.Project(context.Background(), binding, workload); err == nil {
+		t.Fatal("expected an error for a resolved binding with Spec.Mappings but no mapping.Reconciler, got nil")
+	}
+}
+
+func TestProject_AppliesConfiguredMappings(t *testing.T) {
+	store := newRecordingSecretStore(map[string][]byte{"host": []byte("db.example.com")})
+	p := New(fakeMappingSource{}, mapping.NewReconciler(store))
+	binding := &servicebindingv1.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-binding", Namespace: "default"},
+		Spec: servicebindingv1.ServiceBindingSpec{
+			Workload: servicebindingv1.ServiceBindingWorkloadReference{Kind: "Deployment", Name: "my-app"},
+			Mappings: []servicebindingv1.ServiceBindingMapping{
+				{Name: "uri", Value: "{{ .host }}"},
+			},
+		},
+		Status: servicebindingv1.ServiceBindingStatus{
+			Binding: &servicebindingv1.ServiceBindingSecretReference{Name: "my-secret"},
+		},
+	}
+	workload := newTestDeployment()
+
+	if err := p.Project(context.Background(), binding, workload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.writes != 1 {
+		t.Errorf("writes = %d, want 1", store.writes)
+	}
+	if len(workload.Spec.Template.Spec.Volumes) != 1 {
+		t.Fatalf("Volumes = %+v, want exactly 1 projected volume", workload.Spec.Template.Spec.Volumes)
+	}
+	sources := workload.Spec.Template.Spec.Volumes[0].Projected.Sources
+	if len(sources) != 2 {
+		t.Fatalf("projected sources = %+v, want the raw secret and the derived mappings secret", sources)
+	}
+}
+
+type recordingSecretStore struct {
+	data   map[string][]byte
+	writes int
+}
+
+func newRecordingSecretStore(data map[string][]byte) *recordingSecretStore {
+	return &recordingSecretStore{data: data}
+}
+
+func (s *recordingSecretStore) GetData(ctx context.Context, namespace, name string) (map[string][]byte, error) {
+	return s.data, nil
+}
+
+func (s *recordingSecretStore) GetAnnotation(ctx context.Context, namespace, name, key string) (string, bool, error) {
+	return "", false, nil
+}
+
+func (s *recordingSecretStore) CreateOrUpdate(ctx context.Context, namespace, name string, data map[string][]byte, annotations map[string]string) error {
+	s.writes++
+	return nil
+}
+
+func TestIsContainerBindable(t *testing.T) {
+	p := &serviceBindingProjector{}
+	name := "app"
+
+	t.Run("defaults to Container kind when no selector is configured", func(t *testing.T) {
+		binding := &servicebindingv1.ServiceBinding{}
+		if !p.isContainerBindable(binding, &metaContainer{Name: &name}, servicebindingv1.ContainerKindContainer) {
+			t.Error("expected a Container to be bindable by default")
+		}
+		if p.isContainerBindable(binding, &metaContainer{Name: &name}, servicebindingv1.ContainerKindInitContainer) {
+			t.Error("expected an InitContainer to not be bindable by default")
+		}
+	})
+
+	t.Run("an InitContainers selector restricts InitContainer bindability by name", func(t *testing.T) {
+		binding := &servicebindingv1.ServiceBinding{Spec: servicebindingv1.ServiceBindingSpec{
+			Workload: servicebindingv1.ServiceBindingWorkloadReference{InitContainers: []string{}},
+		}}
+		// InitContainers is configured (non-nil via containerNamesForKind contract) only when len != 0;
+		// an explicitly empty slice falls back to the ContainerKind default, so use a populated
+		// selector to exercise the "named selector configured" path instead.
+		binding.Spec.Workload.InitContainers = []string{"app"}
+		if !p.isContainerBindable(binding, &metaContainer{Name: &name}, servicebindingv1.ContainerKindInitContainer) {
+			t.Error("expected the named InitContainer to be bindable")
+		}
+		other := "sidecar"
+		if p.isContainerBindable(binding, &metaContainer{Name: &other}, servicebindingv1.ContainerKindInitContainer) {
+			t.Error("expected an InitContainer not named in the selector to not be bindable")
+		}
+	})
+
+	t.Run("a Containers selector restricts Container kind bindability by name", func(t *testing.T) {
+		binding := &servicebindingv1.ServiceBinding{Spec: servicebindingv1.ServiceBindingSpec{
+			Workload: servicebindingv1.ServiceBindingWorkloadReference{Containers: []string{"app"}},
+		}}
+		other := "sidecar"
+		if !p.isContainerBindable(binding, &metaContainer{Name: &name}, servicebindingv1.ContainerKindContainer) {
+			t.Error("expected the named Container to be bindable")
+		}
+		if p.isContainerBindable(binding, &metaContainer{Name: &other}, servicebindingv1.ContainerKindContainer) {
+			t.Error("expected a Container not named in the selector to not be bindable")
+		}
+	})
+}
+
+func TestDownwardAPIFieldPath(t *testing.T) {
+	cases := []struct {
+		key           string
+		wantFieldPath string
+		wantVolumeOK  bool
+	}{
+		{"pod.name", "metadata.name", true},
+		{"pod.namespace", "metadata.namespace", true},
+		{"pod.uid", "metadata.uid", true},
+		{"pod.ip", "status.podIP", false},
+		{"pod.ips", "status.podIPs", false},
+		{"pod.serviceAccountName", "spec.serviceAccountName", false},
+		{"node.name", "spec.nodeName", false},
+		{"pod.labels['app.kubernetes.io/name']", "metadata.labels['app.kubernetes.io/name']", true},
+		{"pod.annotations['my/annotation']", "metadata.annotations['my/annotation']", true},
+		{"not.a.reserved.key", "", false},
+	}
+	for _, c := range cases {
+		fieldPath, volumeOK := downwardAPIFieldPath(c.key)
+		if fieldPath != c.wantFieldPath || volumeOK != c.wantVolumeOK {
+			t.Errorf("downwardAPIFieldPath(%q) = (%q, %v), want (%q, %v)", c.key, fieldPath, volumeOK, c.wantFieldPath, c.wantVolumeOK)
+		}
+	}
+}
+
+func TestDownwardAPIVolumePath(t *testing.T) {
+	cases := map[string]string{
+		"pod.name":                             "pod.name",
+		"pod.labels['app.kubernetes.io/name']": "pod.labels['app.kubernetes.io_name']",
+		"pod.annotations['my/annotation']":     "pod.annotations['my_annotation']",
+	}
+	for key, want := range cases {
+		if got := downwardAPIVolumePath(key); got != want {
+			t.Errorf("downwardAPIVolumePath(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestProjectVolumeMount_Mounts(t *testing.T) {
+	p := &serviceBindingProjector{}
+	binding := &servicebindingv1.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-binding"},
+		Spec: servicebindingv1.ServiceBindingSpec{
+			Mounts: []servicebindingv1.ServiceBindingMount{
+				{Key: "username", Path: "/etc/legacy/username"},
+				{Key: "password", Path: "/etc/legacy/password", Container: "other"},
+			},
+		},
+	}
+	name := "app"
+	mc := &metaContainer{Name: &name}
+
+	p.projectVolumeMount(binding, mc)
+
+	var gotSubPaths []string
+	for _, vm := range mc.VolumeMounts {
+		if vm.SubPath != "" {
+			gotSubPaths = append(gotSubPaths, vm.SubPath+"@"+vm.MountPath)
+		}
+	}
+	if len(gotSubPaths) != 1 || gotSubPaths[0] != "username@/etc/legacy/username" {
+		t.Errorf("subPath mounts = %v, want exactly the unrestricted username mount (the password mount is restricted to container %q)", gotSubPaths, "other")
+	}
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projector
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	servicebindingv1 "github.com/servicebinding/runtime/apis/v1"
+)
+
+// ServiceBindingProjector projects and unprojects a ServiceBinding's secret into a workload.
+type ServiceBindingProjector interface {
+	// Project the binding secret into the workload, first unprojecting any existing projection of
+	// this binding so the workload always reflects the binding's current desired state.
+	Project(ctx context.Context, binding *servicebindingv1.ServiceBinding, workload runtime.Object) error
+
+	// Unproject removes a previously projected binding secret from the workload.
+	Unproject(ctx context.Context, binding *servicebindingv1.ServiceBinding, workload runtime.Object) error
+
+	// IsProjected returns true if the binding is currently projected into the workload.
+	IsProjected(ctx context.Context, binding *servicebindingv1.ServiceBinding, workload runtime.Object) bool
+}
+
+// MappingSource resolves the REST mapping and ClusterWorkloadResourceMapping for a workload so that
+// non-PodSpec-convention resources can still be projected into.
+type MappingSource interface {
+	// LookupRESTMapping resolves the REST mapping for the workload's GroupVersionKind.
+	LookupRESTMapping(ctx context.Context, workload runtime.Object) (*meta.RESTMapping, error)
+
+	// LookupWorkloadMapping resolves the ClusterWorkloadResourceMapping for the resource, if any.
+	// A nil result indicates the workload follows the standard PodSpec convention.
+	LookupWorkloadMapping(ctx context.Context, resource schema.GroupVersionResource) (*servicebindingv1.ClusterWorkloadResourceMappingSpec, error)
+}
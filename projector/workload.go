@@ -0,0 +1,376 @@
+/*
+Copyright 2021 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	servicebindingv1 "github.com/servicebinding/runtime/apis/v1"
+)
+
+// defaultPodSpecMapping is used for workloads that follow the conventional
+// .spec.template.{metadata,spec} PodTemplateSpec shape.
+var defaultPodSpecMapping = servicebindingv1.ClusterWorkloadResourceMappingTemplate{
+	Annotations: strPtr(".spec.template.metadata.annotations"),
+	Containers: []servicebindingv1.ClusterWorkloadResourceMappingContainer{
+		{Path: ".spec.template.spec.containers[*]"},
+	},
+	InitContainers: []servicebindingv1.ClusterWorkloadResourceMappingContainer{
+		{Path: ".spec.template.spec.initContainers[*]"},
+	},
+	EphemeralContainers: []servicebindingv1.ClusterWorkloadResourceMappingContainer{
+		{Path: ".spec.template.spec.ephemeralContainers[*]"},
+	},
+	Volumes: strPtr(".spec.template.spec.volumes"),
+}
+
+func strPtr(s string) *string { return &s }
+
+// MappingVersion selects the mapping template for the resolved resource version, falling back to
+// the conventional PodSpec shape when the workload has no ClusterWorkloadResourceMapping, or to the
+// wildcard ("") entry when the specific version isn't listed.
+func MappingVersion(version string, mapping *servicebindingv1.ClusterWorkloadResourceMappingSpec) *servicebindingv1.ClusterWorkloadResourceMappingTemplate {
+	if mapping == nil {
+		return &defaultPodSpecMapping
+	}
+	var wildcard *servicebindingv1.ClusterWorkloadResourceMappingTemplate
+	for i := range mapping.Versions {
+		v := &mapping.Versions[i]
+		if v.Version == version {
+			return v
+		}
+		if v.Version == "" || v.Version == "*" {
+			wildcard = v
+		}
+	}
+	if wildcard != nil {
+		return wildcard
+	}
+	return &defaultPodSpecMapping
+}
+
+// metaContainer is a view over a single Container, InitContainer, or EphemeralContainer entry
+// within a workload, regardless of the underlying resource's shape.
+type metaContainer struct {
+	Name         *string
+	Env          []corev1.EnvVar
+	VolumeMounts []corev1.VolumeMount
+
+	source map[string]interface{}
+}
+
+// metaPodTemplate is a normalized view over the PodTemplateSpec-like portion of a workload,
+// resolved according to a ClusterWorkloadResourceMappingTemplate.
+type metaPodTemplate struct {
+	Containers          []metaContainer
+	InitContainers      []metaContainer
+	EphemeralContainers []metaContainer
+	Volumes             []corev1.Volume
+
+	PodTemplateAnnotations map[string]string
+	WorkloadAnnotations    map[string]string
+
+	// original is the runtime.Object passed to NewMetaPodTemplate. When it isn't already
+	// *unstructured.Unstructured, WriteToWorkload converts the mutated workload map back into it
+	// so typed callers actually observe the projection.
+	original runtime.Object
+	workload *unstructured.Unstructured
+	mapping  *servicebindingv1.ClusterWorkloadResourceMappingTemplate
+}
+
+// NewMetaPodTemplate resolves a metaPodTemplate for the workload according to the mapping.
+func NewMetaPodTemplate(ctx context.Context, workload runtime.Object, mapping *servicebindingv1.ClusterWorkloadResourceMappingTemplate) (*metaPodTemplate, error) {
+	u, err := toUnstructured(workload)
+	if err != nil {
+		return nil, err
+	}
+
+	mpt := &metaPodTemplate{
+		original: workload,
+		workload: u,
+		mapping:  mapping,
+	}
+
+	annotationsPath := ".metadata.annotations"
+	if mapping.Annotations != nil {
+		annotationsPath = *mapping.Annotations
+	}
+	podAnnotations, _, err := nestedStringMap(u.Object, annotationsPath)
+	if err != nil {
+		return nil, err
+	}
+	mpt.PodTemplateAnnotations = podAnnotations
+
+	workloadAnnotations := u.GetAnnotations()
+	if workloadAnnotations == nil {
+		workloadAnnotations = map[string]string{}
+	}
+	mpt.WorkloadAnnotations = workloadAnnotations
+
+	mpt.Containers, err = resolveContainers(u.Object, mapping.Containers)
+	if err != nil {
+		return nil, err
+	}
+	mpt.InitContainers, err = resolveContainers(u.Object, mapping.InitContainers)
+	if err != nil {
+		return nil, err
+	}
+	mpt.EphemeralContainers, err = resolveContainers(u.Object, mapping.EphemeralContainers)
+	if err != nil {
+		return nil, err
+	}
+
+	volumesPath := ".spec.template.spec.volumes"
+	if mapping.Volumes != nil {
+		volumesPath = *mapping.Volumes
+	}
+	mpt.Volumes, err = nestedVolumes(u.Object, volumesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return mpt, nil
+}
+
+// WriteToWorkload flushes the normalized view back onto the underlying workload object.
+func (mpt *metaPodTemplate) WriteToWorkload(ctx context.Context) error {
+	if err := writeContainers(mpt.workload.Object, mpt.mapping.Containers, mpt.Containers); err != nil {
+		return err
+	}
+	if err := writeContainers(mpt.workload.Object, mpt.mapping.InitContainers, mpt.InitContainers); err != nil {
+		return err
+	}
+	if err := writeContainers(mpt.workload.Object, mpt.mapping.EphemeralContainers, mpt.EphemeralContainers); err != nil {
+		return err
+	}
+
+	volumesPath := ".spec.template.spec.volumes"
+	if mpt.mapping.Volumes != nil {
+		volumesPath = *mpt.mapping.Volumes
+	}
+	if err := setNestedVolumes(mpt.workload.Object, volumesPath, mpt.Volumes); err != nil {
+		return err
+	}
+
+	annotationsPath := ".metadata.annotations"
+	if mpt.mapping.Annotations != nil {
+		annotationsPath = *mpt.mapping.Annotations
+	}
+	if err := setNestedStringMap(mpt.workload.Object, annotationsPath, mpt.PodTemplateAnnotations); err != nil {
+		return err
+	}
+	mpt.workload.SetAnnotations(mpt.WorkloadAnnotations)
+
+	return writeBackToOriginal(mpt.original, mpt.workload)
+}
+
+func resolveContainers(obj map[string]interface{}, mappings []servicebindingv1.ClusterWorkloadResourceMappingContainer) ([]metaContainer, error) {
+	containers := []metaContainer{}
+	for _, cm := range mappings {
+		items, _, err := nestedSliceByPath(obj, strings.TrimSuffix(cm.Path, "[*]"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve containers at %q: %w", cm.Path, err)
+		}
+		for _, item := range items {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			containers = append(containers, toMetaContainer(m, cm))
+		}
+	}
+	return containers, nil
+}
+
+func writeContainers(obj map[string]interface{}, mappings []servicebindingv1.ClusterWorkloadResourceMappingContainer, containers []metaContainer) error {
+	// each mapping path owns a contiguous slice of the flattened containers, in order
+	idx := 0
+	for _, cm := range mappings {
+		items, found, err := nestedSliceByPath(obj, strings.TrimSuffix(cm.Path, "[*]"))
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+		for i := range items {
+			if idx >= len(containers) {
+				break
+			}
+			items[i] = fromMetaContainer(containers[idx], cm)
+			idx++
+		}
+		if err := setNestedSliceByPath(obj, strings.TrimSuffix(cm.Path, "[*]"), items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toMetaContainer(m map[string]interface{}, cm servicebindingv1.ClusterWorkloadResourceMappingContainer) metaContainer {
+	mc := metaContainer{source: m}
+	namePath := "name"
+	if cm.Name != nil {
+		namePath = *cm.Name
+	}
+	if name, ok, _ := unstructured.NestedString(m, strings.Split(strings.TrimPrefix(namePath, "."), ".")...); ok {
+		mc.Name = &name
+	}
+
+	envPath := "env"
+	if cm.Env != nil {
+		envPath = *cm.Env
+	}
+	if raw, ok, _ := unstructured.NestedSlice(m, strings.Split(strings.TrimPrefix(envPath, "."), ".")...); ok {
+		var env []corev1.EnvVar
+		if err := fromUnstructuredSlice(raw, &env); err == nil {
+			mc.Env = env
+		}
+	}
+
+	vmPath := "volumeMounts"
+	if cm.VolumeMounts != nil {
+		vmPath = *cm.VolumeMounts
+	}
+	if raw, ok, _ := unstructured.NestedSlice(m, strings.Split(strings.TrimPrefix(vmPath, "."), ".")...); ok {
+		var vm []corev1.VolumeMount
+		if err := fromUnstructuredSlice(raw, &vm); err == nil {
+			mc.VolumeMounts = vm
+		}
+	}
+
+	return mc
+}
+
+func fromMetaContainer(mc metaContainer, cm servicebindingv1.ClusterWorkloadResourceMappingContainer) map[string]interface{} {
+	m := mc.source
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+
+	envPath := "env"
+	if cm.Env != nil {
+		envPath = *cm.Env
+	}
+	env, _ := toUnstructuredSlice(mc.Env)
+	_ = unstructured.SetNestedSlice(m, env, strings.Split(strings.TrimPrefix(envPath, "."), ".")...)
+
+	vmPath := "volumeMounts"
+	if cm.VolumeMounts != nil {
+		vmPath = *cm.VolumeMounts
+	}
+	vm, _ := toUnstructuredSlice(mc.VolumeMounts)
+	_ = unstructured.SetNestedSlice(m, vm, strings.Split(strings.TrimPrefix(vmPath, "."), ".")...)
+
+	return m
+}
+
+func toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: raw}, nil
+}
+
+// writeBackToOriginal copies the (possibly mutated) unstructured workload map back onto original,
+// unless original already *is* u, in which case the mutations already landed in place.
+func writeBackToOriginal(original runtime.Object, u *unstructured.Unstructured) error {
+	if _, ok := original.(*unstructured.Unstructured); ok {
+		// mutations were applied in-place on the same object the caller holds
+		return nil
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, original)
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "."), ".")
+}
+
+func nestedStringMap(obj map[string]interface{}, path string) (map[string]string, bool, error) {
+	v, found, err := unstructured.NestedStringMap(obj, splitPath(path)...)
+	if v == nil {
+		v = map[string]string{}
+	}
+	return v, found, err
+}
+
+func setNestedStringMap(obj map[string]interface{}, path string, value map[string]string) error {
+	if len(value) == 0 {
+		unstructured.RemoveNestedField(obj, splitPath(path)...)
+		return nil
+	}
+	return unstructured.SetNestedStringMap(obj, value, splitPath(path)...)
+}
+
+func nestedSliceByPath(obj map[string]interface{}, path string) ([]interface{}, bool, error) {
+	return unstructured.NestedSlice(obj, splitPath(path)...)
+}
+
+func setNestedSliceByPath(obj map[string]interface{}, path string, value []interface{}) error {
+	return unstructured.SetNestedSlice(obj, value, splitPath(path)...)
+}
+
+func nestedVolumes(obj map[string]interface{}, path string) ([]corev1.Volume, error) {
+	raw, found, err := unstructured.NestedSlice(obj, splitPath(path)...)
+	if err != nil || !found {
+		return nil, err
+	}
+	var volumes []corev1.Volume
+	if err := fromUnstructuredSlice(raw, &volumes); err != nil {
+		return nil, err
+	}
+	return volumes, nil
+}
+
+func setNestedVolumes(obj map[string]interface{}, path string, volumes []corev1.Volume) error {
+	raw, err := toUnstructuredSlice(volumes)
+	if err != nil {
+		return err
+	}
+	if len(raw) == 0 {
+		unstructured.RemoveNestedField(obj, splitPath(path)...)
+		return nil
+	}
+	return unstructured.SetNestedSlice(obj, raw, splitPath(path)...)
+}
+
+func fromUnstructuredSlice(raw []interface{}, out interface{}) error {
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]interface{}{"items": raw}, &struct {
+		Items interface{} `json:"items"`
+	}{Items: out})
+}
+
+func toUnstructuredSlice(in interface{}) ([]interface{}, error) {
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&struct {
+		Items interface{} `json:"items"`
+	}{Items: in})
+	if err != nil {
+		return nil, err
+	}
+	items, _ := raw["items"].([]interface{})
+	return items, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2021 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resolver discovers the binding Secret for a ServiceBinding's Spec.Service reference,
+// feeding the result to the projector package via ServiceBinding.Status.Binding. It implements the
+// ProvisionedService duck type (https://github.com/servicebinding/spec#provisioned-service) as the
+// primary resolution strategy, falling back to a pluggable chain of ServiceResolvers for resources
+// that don't implement that contract natively (a raw Secret, a Service, a provider CRD, ...).
+package resolver
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ResourceFetcher fetches the referenced resource as Unstructured so both the ProvisionedService
+// duck type and arbitrary ServiceResolvers can inspect it without depending on its Go type.
+type ResourceFetcher interface {
+	Get(ctx context.Context, ref corev1.ObjectReference) (*unstructured.Unstructured, error)
+}
+
+// ServiceResolver extracts or synthesizes the name of the binding Secret for a service reference
+// that doesn't surface `.status.binding.name` itself. Implementations register themselves with a
+// Resolver to teach it about a specific duck-type or native resource kind (Secret, Service, a
+// provider's custom resource, ...) without the resolver package needing to know about it upfront.
+type ServiceResolver interface {
+	// Resolve returns the name of the Secret backing ref, or "" if this resolver doesn't apply to
+	// ref. A non-nil error aborts resolution; returning ("", nil) lets the chain continue.
+	Resolve(ctx context.Context, ref corev1.ObjectReference) (secretName string, err error)
+}
+
+// Resolver resolves a ServiceBinding's Spec.Service reference to the name of the Secret carrying
+// its binding workload, per the ProvisionedService duck type, falling back to chain in order.
+type Resolver struct {
+	fetcher ResourceFetcher
+	chain   []ServiceResolver
+}
+
+// New creates a Resolver backed by fetcher, consulting chain in order for resources that don't
+// implement the ProvisionedService contract themselves.
+func New(fetcher ResourceFetcher, chain ...ServiceResolver) *Resolver {
+	return &Resolver{
+		fetcher: fetcher,
+		chain:   chain,
+	}
+}
+
+// Resolve returns the name of the Secret backing ref.
+func (r *Resolver) Resolve(ctx context.Context, ref corev1.ObjectReference) (string, error) {
+	u, err := r.fetcher.Get(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	if name, found, err := unstructured.NestedString(u.Object, "status", "binding", "name"); err != nil {
+		return "", fmt.Errorf("unable to read status.binding.name for %s %s/%s: %w", ref.Kind, ref.Namespace, ref.Name, err)
+	} else if found && name != "" {
+		return name, nil
+	}
+
+	for _, sr := range r.chain {
+		name, err := sr.Resolve(ctx, ref)
+		if err != nil {
+			return "", err
+		}
+		if name != "" {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s %s/%s does not implement the ProvisionedService duck type and no ServiceResolver claimed it", ref.Kind, ref.Namespace, ref.Name)
+}
+
+// NativeSecretResolver resolves a direct reference to a v1 Secret: the Secret referenced *is* the
+// binding Secret, so its own name is returned verbatim.
+type NativeSecretResolver struct{}
+
+func (NativeSecretResolver) Resolve(ctx context.Context, ref corev1.ObjectReference) (string, error) {
+	if ref.APIVersion != "v1" || ref.Kind != "Secret" {
+		return "", nil
+	}
+	return ref.Name, nil
+}
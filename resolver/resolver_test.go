@@ -0,0 +1,133 @@
+/*
+Copyright 2021 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type fakeFetcher struct {
+	objs map[string]*unstructured.Unstructured
+}
+
+func (f fakeFetcher) Get(ctx context.Context, ref corev1.ObjectReference) (*unstructured.Unstructured, error) {
+	u, ok := f.objs[ref.Namespace+"/"+ref.Name]
+	if !ok {
+		return nil, fmt.Errorf("%s %s/%s not found", ref.Kind, ref.Namespace, ref.Name)
+	}
+	return u, nil
+}
+
+type fakeResolver struct {
+	name string
+	err  error
+}
+
+func (f fakeResolver) Resolve(ctx context.Context, ref corev1.ObjectReference) (string, error) {
+	return f.name, f.err
+}
+
+func provisionedService(secretName string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"binding": map[string]interface{}{
+				"name": secretName,
+			},
+		},
+	}}
+}
+
+func TestResolve(t *testing.T) {
+	ref := corev1.ObjectReference{Kind: "Database", Namespace: "default", Name: "my-db"}
+
+	t.Run("resolves via the ProvisionedService duck type", func(t *testing.T) {
+		fetcher := fakeFetcher{objs: map[string]*unstructured.Unstructured{
+			"default/my-db": provisionedService("my-db-secret"),
+		}}
+		r := New(fetcher)
+		name, err := r.Resolve(context.Background(), ref)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name != "my-db-secret" {
+			t.Errorf("name = %q, want %q", name, "my-db-secret")
+		}
+	})
+
+	t.Run("falls back to the resolver chain when the duck type is absent", func(t *testing.T) {
+		fetcher := fakeFetcher{objs: map[string]*unstructured.Unstructured{
+			"default/my-db": {Object: map[string]interface{}{}},
+		}}
+		r := New(fetcher, fakeResolver{name: ""}, fakeResolver{name: "chained-secret"})
+		name, err := r.Resolve(context.Background(), ref)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name != "chained-secret" {
+			t.Errorf("name = %q, want %q", name, "chained-secret")
+		}
+	})
+
+	t.Run("a chained resolver error aborts resolution", func(t *testing.T) {
+		fetcher := fakeFetcher{objs: map[string]*unstructured.Unstructured{
+			"default/my-db": {Object: map[string]interface{}{}},
+		}}
+		r := New(fetcher, fakeResolver{err: fmt.Errorf("boom")})
+		if _, err := r.Resolve(context.Background(), ref); err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	t.Run("no resolver claims the reference", func(t *testing.T) {
+		fetcher := fakeFetcher{objs: map[string]*unstructured.Unstructured{
+			"default/my-db": {Object: map[string]interface{}{}},
+		}}
+		r := New(fetcher)
+		if _, err := r.Resolve(context.Background(), ref); err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
+
+func TestNativeSecretResolver(t *testing.T) {
+	t.Run("resolves a Secret reference to its own name", func(t *testing.T) {
+		ref := corev1.ObjectReference{APIVersion: "v1", Kind: "Secret", Namespace: "default", Name: "my-secret"}
+		name, err := NativeSecretResolver{}.Resolve(context.Background(), ref)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name != "my-secret" {
+			t.Errorf("name = %q, want %q", name, "my-secret")
+		}
+	})
+
+	t.Run("declines non-Secret references", func(t *testing.T) {
+		ref := corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "my-config"}
+		name, err := NativeSecretResolver{}.Resolve(context.Background(), ref)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name != "" {
+			t.Errorf("name = %q, want empty", name)
+		}
+	})
+}
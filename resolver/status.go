@@ -0,0 +1,62 @@
+/*
+Copyright 2021 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	servicebindingv1 "github.com/servicebinding/runtime/apis/v1"
+	"github.com/servicebinding/runtime/mapping"
+)
+
+// SecretDataFetcher fetches the data of the resolved Secret so ResolveAndHash can compute a
+// content hash for rotation detection without the caller having to do its own lookup.
+type SecretDataFetcher interface {
+	GetData(ctx context.Context, namespace, name string) (map[string][]byte, error)
+}
+
+// ResolveAndHash resolves ref to a Secret name via r, then fetches that Secret's data through
+// secrets to compute a content hash. The returned hash lets a reconciler tell whether a
+// previously-resolved binding needs to be re-projected because its Secret rotated.
+func (r *Resolver) ResolveAndHash(ctx context.Context, ref corev1.ObjectReference, secrets SecretDataFetcher) (name string, hash string, err error) {
+	name, err = r.Resolve(ctx, ref)
+	if err != nil {
+		return "", "", err
+	}
+	data, err := secrets.GetData(ctx, ref.Namespace, name)
+	if err != nil {
+		return "", "", err
+	}
+	return name, mapping.Hash(data), nil
+}
+
+// UpdateStatus applies a freshly resolved secretName/contentHash pair to binding.Status.Binding,
+// reporting whether the status actually changed so a caller knows whether it needs to persist the
+// update and re-trigger the projector.
+func UpdateStatus(binding *servicebindingv1.ServiceBinding, secretName, contentHash string) bool {
+	current := binding.Status.Binding
+	if current != nil && current.Name == secretName && current.Hash == contentHash {
+		return false
+	}
+	binding.Status.Binding = &servicebindingv1.ServiceBindingSecretReference{
+		Name: secretName,
+		Hash: contentHash,
+	}
+	return true
+}
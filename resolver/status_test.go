@@ -0,0 +1,115 @@
+/*
+Copyright 2021 the original author or authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	servicebindingv1 "github.com/servicebinding/runtime/apis/v1"
+)
+
+type fakeSecretDataFetcher struct {
+	data map[string][]byte
+	err  error
+}
+
+func (f fakeSecretDataFetcher) GetData(ctx context.Context, namespace, name string) (map[string][]byte, error) {
+	return f.data, f.err
+}
+
+func TestResolveAndHash(t *testing.T) {
+	ref := corev1.ObjectReference{Kind: "Database", Namespace: "default", Name: "my-db"}
+
+	t.Run("resolves the name and hashes the secret data", func(t *testing.T) {
+		fetcher := fakeFetcher{objs: map[string]*unstructured.Unstructured{
+			"default/my-db": provisionedService("my-db-secret"),
+		}}
+		r := New(fetcher)
+		secrets := fakeSecretDataFetcher{data: map[string][]byte{"username": []byte("guest")}}
+
+		name, hash, err := r.ResolveAndHash(context.Background(), ref, secrets)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name != "my-db-secret" {
+			t.Errorf("name = %q, want %q", name, "my-db-secret")
+		}
+		if hash == "" {
+			t.Error("hash = \"\", want non-empty")
+		}
+	})
+
+	t.Run("surfaces a resolve error", func(t *testing.T) {
+		fetcher := fakeFetcher{objs: map[string]*unstructured.Unstructured{}}
+		r := New(fetcher)
+		if _, _, err := r.ResolveAndHash(context.Background(), ref, fakeSecretDataFetcher{}); err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	t.Run("surfaces a secret fetch error", func(t *testing.T) {
+		fetcher := fakeFetcher{objs: map[string]*unstructured.Unstructured{
+			"default/my-db": provisionedService("my-db-secret"),
+		}}
+		r := New(fetcher)
+		secrets := fakeSecretDataFetcher{err: fmt.Errorf("boom")}
+		if _, _, err := r.ResolveAndHash(context.Background(), ref, secrets); err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
+
+func TestUpdateStatus(t *testing.T) {
+	t.Run("reports a change and applies it when the binding is unset", func(t *testing.T) {
+		binding := &servicebindingv1.ServiceBinding{}
+		changed := UpdateStatus(binding, "my-secret", "abc123")
+		if !changed {
+			t.Error("changed = false, want true")
+		}
+		if binding.Status.Binding == nil || binding.Status.Binding.Name != "my-secret" || binding.Status.Binding.Hash != "abc123" {
+			t.Errorf("Status.Binding = %+v, want {Name: my-secret, Hash: abc123}", binding.Status.Binding)
+		}
+	})
+
+	t.Run("reports no change when name and hash already match", func(t *testing.T) {
+		binding := &servicebindingv1.ServiceBinding{Status: servicebindingv1.ServiceBindingStatus{
+			Binding: &servicebindingv1.ServiceBindingSecretReference{Name: "my-secret", Hash: "abc123"},
+		}}
+		changed := UpdateStatus(binding, "my-secret", "abc123")
+		if changed {
+			t.Error("changed = true, want false")
+		}
+	})
+
+	t.Run("reports a change when the hash rotated", func(t *testing.T) {
+		binding := &servicebindingv1.ServiceBinding{Status: servicebindingv1.ServiceBindingStatus{
+			Binding: &servicebindingv1.ServiceBindingSecretReference{Name: "my-secret", Hash: "abc123"},
+		}}
+		changed := UpdateStatus(binding, "my-secret", "def456")
+		if !changed {
+			t.Error("changed = false, want true")
+		}
+		if binding.Status.Binding.Hash != "def456" {
+			t.Errorf("Hash = %q, want %q", binding.Status.Binding.Hash, "def456")
+		}
+	})
+}